@@ -7,16 +7,51 @@ import (
 	"os"
 	"strings"
 
-	"github.com/suzuki-shunsuke/go-ci-env/cienv"
+	"github.com/mercari/tfnotify/internal/cienv"
+	upstreamcienv "github.com/suzuki-shunsuke/go-ci-env/cienv"
 	"github.com/suzuki-shunsuke/go-findconfig/findconfig"
 	"gopkg.in/yaml.v2"
 )
 
+// ciPlatform is the subset of upstreamcienv.Platform and cienv.Platform that
+// config.go relies on, so Complement/Validation can fall back to the
+// internal cienv adapter for CI platforms go-ci-env doesn't support yet
+type ciPlatform interface {
+	CI() string
+	RepoOwner() string
+	RepoName() string
+}
+
+// platformByName resolves name against go-ci-env first, falling back to
+// the internal cienv adapter for platforms upstream doesn't support yet
+func platformByName(name string) ciPlatform {
+	if platform := upstreamcienv.GetByName(name); platform != nil {
+		return platform
+	}
+	if platform := cienv.GetByName(name); platform != nil {
+		return platform
+	}
+	return nil
+}
+
+// detectPlatform auto-detects the running CI platform the same way,
+// trying go-ci-env first and the internal cienv adapter second
+func detectPlatform() ciPlatform {
+	if platform := upstreamcienv.Get(); platform != nil {
+		return platform
+	}
+	if platform := cienv.Get(); platform != nil {
+		return platform
+	}
+	return nil
+}
+
 // Config is for tfnotify config structure
 type Config struct {
 	CI        string            `yaml:"ci"`
 	Notifier  Notifier          `yaml:"notifier"`
 	Terraform Terraform         `yaml:"terraform"`
+	Templates map[string]string `yaml:"templates,omitempty"` // named partials referenced via {{ template "name" . }}
 	Vars      map[string]string `yaml:"-"`
 
 	path string
@@ -24,10 +59,13 @@ type Config struct {
 
 // Notifier is a notification notifier
 type Notifier struct {
-	Github   GithubNotifier   `yaml:"github"`
-	Gitlab   GitlabNotifier   `yaml:"gitlab"`
-	Slack    SlackNotifier    `yaml:"slack"`
-	Typetalk TypetalkNotifier `yaml:"typetalk"`
+	Github    GithubNotifier    `yaml:"github"`
+	Gitlab    GitlabNotifier    `yaml:"gitlab"`
+	Slack     SlackNotifier     `yaml:"slack"`
+	Typetalk  TypetalkNotifier  `yaml:"typetalk"`
+	Email     EmailNotifier     `yaml:"email"`
+	Webhook   WebhookNotifier   `yaml:"webhook"`
+	Bitbucket BitbucketNotifier `yaml:"bitbucket"`
 }
 
 // GithubNotifier is a notifier for GitHub
@@ -44,7 +82,16 @@ type GitlabNotifier struct {
 	Repository Repository `yaml:"repository"`
 }
 
-// Repository represents a GitHub repository
+// BitbucketNotifier is a notifier for Bitbucket Cloud
+type BitbucketNotifier struct {
+	Username   string     `yaml:"username"`
+	Token      string     `yaml:"token"` // app password
+	BaseURL    string     `yaml:"base_url"`
+	Repository Repository `yaml:"repository"`
+}
+
+// Repository represents a repository owner/name pair, shared by the
+// GitHub, GitLab, and Bitbucket notifiers
 type Repository struct {
 	Owner string `yaml:"owner"`
 	Name  string `yaml:"name"`
@@ -63,6 +110,36 @@ type TypetalkNotifier struct {
 	TopicID string `yaml:"topic_id"`
 }
 
+// EmailNotifier is a notifier that delivers notifications over SMTP
+type EmailNotifier struct {
+	Host        string   `yaml:"host"`
+	Port        int      `yaml:"port"`
+	Username    string   `yaml:"username"`
+	Password    string   `yaml:"password"`
+	Encryption  string   `yaml:"encryption,omitempty"`   // one of: "tls", "starttls", "none" (default: "starttls")
+	ContentType string   `yaml:"content_type,omitempty"` // one of: "html", "text" (default: "html")
+	From        string   `yaml:"from"`
+	To          []string `yaml:"to"`
+}
+
+// WebhookNotifier is a generic notifier that posts a templated JSON payload
+// to an arbitrary URL (MS Teams, Discord, PagerDuty, Opsgenie, or a
+// user-owned service), optionally HMAC-signed
+type WebhookNotifier struct {
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method,omitempty"` // default: POST
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Secret  string            `yaml:"secret,omitempty"` // HMAC-SHA256 key; the body's signature is sent in X-Tfnotify-Signature
+	Body    string            `yaml:"body,omitempty"`   // Go template rendered with the same data as Terraform.Plan/Apply templates
+	Retry   WebhookRetry      `yaml:"retry,omitempty"`
+}
+
+// WebhookRetry is the retry/backoff policy for WebhookNotifier
+type WebhookRetry struct {
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+	BackoffSecs int `yaml:"backoff_seconds,omitempty"`
+}
+
 // Terraform represents terraform configurations
 type Terraform struct {
 	Default      Default `yaml:"default"`
@@ -74,51 +151,60 @@ type Terraform struct {
 
 // Default is a default setting for terraform commands
 type Default struct {
-	Template string `yaml:"template"`
+	Template     string `yaml:"template"`
+	TemplateFile string `yaml:"template_file,omitempty"`
 }
 
 // Fmt is a terraform fmt config
 type Fmt struct {
-	Template string `yaml:"template"`
+	Template     string `yaml:"template"`
+	TemplateFile string `yaml:"template_file,omitempty"`
 }
 
 // Plan is a terraform plan config
 type Plan struct {
 	Template            string              `yaml:"template"`
+	TemplateFile        string              `yaml:"template_file,omitempty"`
 	WhenAddOrUpdateOnly WhenAddOrUpdateOnly `yaml:"when_add_or_update_only,omitempty"`
 	WhenDestroy         WhenDestroy         `yaml:"when_destroy,omitempty"`
 	WhenNoChanges       WhenNoChanges       `yaml:"when_no_changes,omitempty"`
 	WhenPlanError       WhenPlanError       `yaml:"when_plan_error,omitempty"`
+	WhenResourceChanges []Policy            `yaml:"when_resource_changes,omitempty"`
 }
 
 // WhenAddOrUpdateOnly is a configuration to notify the plan result contains new or updated in place resources
 type WhenAddOrUpdateOnly struct {
-	Label string `yaml:"label,omitempty"`
-	Color string `yaml:"label_color,omitempty"`
+	Label      string   `yaml:"label,omitempty"`
+	Color      string   `yaml:"label_color,omitempty"`
+	Recipients []string `yaml:"recipients,omitempty"`
 }
 
 // WhenDestroy is a configuration to notify the plan result contains destroy operation
 type WhenDestroy struct {
-	Label    string `yaml:"label,omitempty"`
-	Template string `yaml:"template,omitempty"`
-	Color    string `yaml:"label_color,omitempty"`
+	Label      string   `yaml:"label,omitempty"`
+	Template   string   `yaml:"template,omitempty"`
+	Color      string   `yaml:"label_color,omitempty"`
+	Recipients []string `yaml:"recipients,omitempty"`
 }
 
 // WhenNoChange is a configuration to add a label when the plan result contains no change
 type WhenNoChanges struct {
-	Label string `yaml:"label,omitempty"`
-	Color string `yaml:"label_color,omitempty"`
+	Label      string   `yaml:"label,omitempty"`
+	Color      string   `yaml:"label_color,omitempty"`
+	Recipients []string `yaml:"recipients,omitempty"`
 }
 
 // WhenPlanError is a configuration to notify the plan result returns an error
 type WhenPlanError struct {
-	Label string `yaml:"label,omitempty"`
-	Color string `yaml:"label_color,omitempty"`
+	Label      string   `yaml:"label,omitempty"`
+	Color      string   `yaml:"label_color,omitempty"`
+	Recipients []string `yaml:"recipients,omitempty"`
 }
 
 // Apply is a terraform apply config
 type Apply struct {
-	Template string `yaml:"template"`
+	Template     string `yaml:"template"`
+	TemplateFile string `yaml:"template_file,omitempty"`
 }
 
 // LoadFile binds the config file to Config structure
@@ -129,18 +215,21 @@ func (cfg *Config) LoadFile(path string) error {
 		return fmt.Errorf("%s: no config file", cfg.path)
 	}
 	raw, _ := ioutil.ReadFile(cfg.path)
-	return yaml.Unmarshal(raw, cfg)
+	if err := yaml.Unmarshal(raw, cfg); err != nil {
+		return err
+	}
+	return cfg.loadTemplateFiles()
 }
 
 func (cfg *Config) Complement() {
-	var platform cienv.Platform
+	var platform ciPlatform
 	if cfg.CI == "" {
-		platform = cienv.Get()
+		platform = detectPlatform()
 		if platform != nil {
 			cfg.CI = platform.CI()
 		}
 	} else {
-		platform = cienv.GetByName(cfg.CI)
+		platform = platformByName(cfg.CI)
 	}
 	if platform == nil {
 		return
@@ -153,6 +242,14 @@ func (cfg *Config) Complement() {
 			cfg.Notifier.Github.Repository.Name = platform.RepoName()
 		}
 	}
+	if cfg.isDefinedBitbucket() {
+		if cfg.Notifier.Bitbucket.Repository.Owner == "" {
+			cfg.Notifier.Bitbucket.Repository.Owner = platform.RepoOwner()
+		}
+		if cfg.Notifier.Bitbucket.Repository.Name == "" {
+			cfg.Notifier.Bitbucket.Repository.Name = platform.RepoName()
+		}
+	}
 }
 
 // Validation validates config file
@@ -178,11 +275,19 @@ func (cfg *Config) Validation() error {
 		// ok pattern
 	case "cloud-build", "cloudbuild":
 		// ok pattern
+	case "bitbucket-pipelines", "bitbucket":
+		// ok pattern
+	case "azure-devops", "azuredevops":
+		// ok pattern
+	case "buildkite":
+		// ok pattern
+	case "codepipeline", "aws-codepipeline":
+		// ok pattern
 	default:
 		return fmt.Errorf("%s: not supported yet", cfg.CI)
 	}
 	if cfg.isDefinedGithub() {
-		platform := cienv.GetByName(cfg.CI)
+		platform := platformByName(cfg.CI)
 
 		if platform != nil {
 			if cfg.Notifier.Github.Repository.Owner == "" {
@@ -208,6 +313,23 @@ func (cfg *Config) Validation() error {
 			return errors.New("repository name is missing")
 		}
 	}
+	if cfg.isDefinedBitbucket() {
+		if platform := platformByName(cfg.CI); platform != nil {
+			if cfg.Notifier.Bitbucket.Repository.Owner == "" {
+				cfg.Notifier.Bitbucket.Repository.Owner = platform.RepoOwner()
+			}
+			if cfg.Notifier.Bitbucket.Repository.Name == "" {
+				cfg.Notifier.Bitbucket.Repository.Name = platform.RepoName()
+			}
+		}
+
+		if cfg.Notifier.Bitbucket.Repository.Owner == "" {
+			return errors.New("repository owner is missing")
+		}
+		if cfg.Notifier.Bitbucket.Repository.Name == "" {
+			return errors.New("repository name is missing")
+		}
+	}
 	if cfg.isDefinedSlack() {
 		if cfg.Notifier.Slack.Channel == "" {
 			return errors.New("slack channel id is missing")
@@ -218,8 +340,24 @@ func (cfg *Config) Validation() error {
 			return errors.New("Typetalk topic id is missing") //nolint:stylecheck
 		}
 	}
-	notifier := cfg.GetNotifierType()
-	if notifier == "" {
+	if cfg.isDefinedEmail() {
+		if cfg.Notifier.Email.Host == "" {
+			return errors.New("email smtp host is missing")
+		}
+		if cfg.Notifier.Email.From == "" {
+			return errors.New("email sender address is missing")
+		}
+		if len(cfg.Notifier.Email.To) == 0 {
+			return errors.New("email recipients are missing")
+		}
+	}
+	if cfg.isDefinedWebhook() {
+		if cfg.Notifier.Webhook.Body == "" {
+			return errors.New("webhook body template is missing")
+		}
+	}
+	notifiers := cfg.GetNotifierType()
+	if len(notifiers) == 0 {
 		return errors.New("notifier is missing")
 	}
 	return nil
@@ -235,6 +373,11 @@ func (cfg *Config) isDefinedGitlab() bool {
 	return cfg.Notifier.Gitlab != (GitlabNotifier{})
 }
 
+func (cfg *Config) isDefinedBitbucket() bool {
+	// not empty
+	return cfg.Notifier.Bitbucket != (BitbucketNotifier{})
+}
+
 func (cfg *Config) isDefinedSlack() bool {
 	// not empty
 	return cfg.Notifier.Slack != (SlackNotifier{})
@@ -245,21 +388,42 @@ func (cfg *Config) isDefinedTypetalk() bool {
 	return cfg.Notifier.Typetalk != (TypetalkNotifier{})
 }
 
-// GetNotifierType return notifier type described in Config
-func (cfg *Config) GetNotifierType() string {
+func (cfg *Config) isDefinedEmail() bool {
+	// EmailNotifier holds a slice, so it isn't comparable with ==
+	return cfg.Notifier.Email.Host != "" || cfg.Notifier.Email.From != "" || len(cfg.Notifier.Email.To) > 0
+}
+
+func (cfg *Config) isDefinedWebhook() bool {
+	// WebhookNotifier holds a map, so it isn't comparable with ==
+	return cfg.Notifier.Webhook.URL != ""
+}
+
+// GetNotifierType returns every notifier type described in Config, so a single
+// plan/apply result can be dispatched to more than one destination at once.
+func (cfg *Config) GetNotifierType() []string {
+	var notifiers []string
 	if cfg.isDefinedGithub() {
-		return "github"
+		notifiers = append(notifiers, "github")
 	}
 	if cfg.isDefinedGitlab() {
-		return "gitlab"
+		notifiers = append(notifiers, "gitlab")
+	}
+	if cfg.isDefinedBitbucket() {
+		notifiers = append(notifiers, "bitbucket")
 	}
 	if cfg.isDefinedSlack() {
-		return "slack"
+		notifiers = append(notifiers, "slack")
 	}
 	if cfg.isDefinedTypetalk() {
-		return "typetalk"
+		notifiers = append(notifiers, "typetalk")
+	}
+	if cfg.isDefinedEmail() {
+		notifiers = append(notifiers, "email")
+	}
+	if cfg.isDefinedWebhook() {
+		notifiers = append(notifiers, "webhook")
 	}
-	return ""
+	return notifiers
 }
 
 // Find returns config path
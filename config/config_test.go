@@ -0,0 +1,164 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetNotifierType(t *testing.T) {
+	testCases := []struct {
+		name     string
+		notifier Notifier
+		want     []string
+	}{
+		{
+			name:     "none configured",
+			notifier: Notifier{},
+			want:     nil,
+		},
+		{
+			name:     "github only",
+			notifier: Notifier{Github: GithubNotifier{Token: "t"}},
+			want:     []string{"github"},
+		},
+		{
+			name: "github and slack fan out together",
+			notifier: Notifier{
+				Github: GithubNotifier{Token: "t"},
+				Slack:  SlackNotifier{Token: "t", Channel: "c"},
+			},
+			want: []string{"github", "slack"},
+		},
+		{
+			name: "every notifier type at once, in GetNotifierType's check order",
+			notifier: Notifier{
+				Github:    GithubNotifier{Token: "t"},
+				Gitlab:    GitlabNotifier{Token: "t"},
+				Bitbucket: BitbucketNotifier{Token: "t"},
+				Slack:     SlackNotifier{Token: "t"},
+				Typetalk:  TypetalkNotifier{Token: "t"},
+				Email:     EmailNotifier{Host: "smtp.example.com"},
+				Webhook:   WebhookNotifier{URL: "https://example.com/hook"},
+			},
+			want: []string{"github", "gitlab", "bitbucket", "slack", "typetalk", "email", "webhook"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{Notifier: tc.notifier}
+			got := cfg.GetNotifierType()
+			if !equalStringSlices(got, tc.want) {
+				t.Errorf("GetNotifierType() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func equalStringSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestValidation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "ci is required",
+			cfg:     Config{},
+			wantErr: "ci: need to be set",
+		},
+		{
+			name:    "unsupported ci",
+			cfg:     Config{CI: "some-unknown-ci"},
+			wantErr: "not supported yet",
+		},
+		{
+			name:    "github missing repository owner",
+			cfg:     Config{CI: "circleci", Notifier: Notifier{Github: GithubNotifier{Token: "t"}}},
+			wantErr: "repository owner is missing",
+		},
+		{
+			name: "gitlab missing repository name",
+			cfg: Config{CI: "circleci", Notifier: Notifier{
+				Gitlab: GitlabNotifier{Token: "t", Repository: Repository{Owner: "o"}},
+			}},
+			wantErr: "repository name is missing",
+		},
+		{
+			name: "bitbucket missing repository owner",
+			cfg: Config{CI: "circleci", Notifier: Notifier{
+				Bitbucket: BitbucketNotifier{Token: "t", Repository: Repository{Name: "n"}},
+			}},
+			wantErr: "repository owner is missing",
+		},
+		{
+			name:    "slack missing channel",
+			cfg:     Config{CI: "circleci", Notifier: Notifier{Slack: SlackNotifier{Token: "t"}}},
+			wantErr: "slack channel id is missing",
+		},
+		{
+			name:    "typetalk missing topic id",
+			cfg:     Config{CI: "circleci", Notifier: Notifier{Typetalk: TypetalkNotifier{Token: "t"}}},
+			wantErr: "Typetalk topic id is missing",
+		},
+		{
+			name:    "email missing host",
+			cfg:     Config{CI: "circleci", Notifier: Notifier{Email: EmailNotifier{From: "a@example.com", To: []string{"b@example.com"}}}},
+			wantErr: "email smtp host is missing",
+		},
+		{
+			name:    "email missing from",
+			cfg:     Config{CI: "circleci", Notifier: Notifier{Email: EmailNotifier{Host: "smtp.example.com", To: []string{"b@example.com"}}}},
+			wantErr: "email sender address is missing",
+		},
+		{
+			name:    "email missing recipients",
+			cfg:     Config{CI: "circleci", Notifier: Notifier{Email: EmailNotifier{Host: "smtp.example.com", From: "a@example.com"}}},
+			wantErr: "email recipients are missing",
+		},
+		{
+			name:    "webhook missing body",
+			cfg:     Config{CI: "circleci", Notifier: Notifier{Webhook: WebhookNotifier{URL: "https://example.com/hook"}}},
+			wantErr: "webhook body template is missing",
+		},
+		{
+			name:    "no notifier configured at all",
+			cfg:     Config{CI: "circleci"},
+			wantErr: "notifier is missing",
+		},
+		{
+			name:    "fully valid slack config",
+			cfg:     Config{CI: "circleci", Notifier: Notifier{Slack: SlackNotifier{Token: "t", Channel: "c"}}},
+			wantErr: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validation()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validation() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validation() = nil, want error containing %q", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validation() = %q, want it to contain %q", err.Error(), tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy is a threshold/gating rule evaluated against a terraform plan
+// summary. Unlike WhenDestroy/WhenNoChanges, several policies can match
+// the same plan at once; their labels are additive rather than mutually
+// exclusive.
+type Policy struct {
+	Label                            string   `yaml:"label,omitempty"`
+	Color                            string   `yaml:"label_color,omitempty"`
+	MaxDestroy                       *int     `yaml:"max_destroy,omitempty"`
+	MaxChanges                       *int     `yaml:"max_changes,omitempty"`
+	ForbidResourceTypes              []string `yaml:"forbid_resource_types,omitempty"`
+	RequiredResourceTypesForApproval []string `yaml:"required_resource_types_for_approval,omitempty"`
+	ExitCode                         int      `yaml:"exit_code,omitempty"`
+}
+
+// ResourceChange is a single resource address/action pair taken from a
+// parsed terraform plan
+type ResourceChange struct {
+	Address string
+	Type    string
+	Action  string // one of: "create", "update", "delete", "no-op"
+}
+
+// PlanSummary is the subset of a parsed terraform plan that policies are
+// evaluated against
+type PlanSummary struct {
+	Changes []ResourceChange
+}
+
+// Destroyed returns the resource changes whose action is "delete"
+func (s PlanSummary) Destroyed() []ResourceChange {
+	return s.withAction("delete")
+}
+
+func (s PlanSummary) withAction(action string) []ResourceChange {
+	var matched []ResourceChange
+	for _, c := range s.Changes {
+		if c.Action == action {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// PolicyResult is the outcome of evaluating a single Policy against a PlanSummary
+type PolicyResult struct {
+	Policy  Policy
+	Matched bool
+	Reasons []string
+}
+
+// Evaluate reports whether the plan summary violates the policy, and why
+func (p Policy) Evaluate(summary PlanSummary) PolicyResult {
+	result := PolicyResult{Policy: p}
+
+	if p.MaxDestroy != nil {
+		if n := len(summary.Destroyed()); n > *p.MaxDestroy {
+			result.Matched = true
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%d resources destroyed, exceeds max_destroy %d", n, *p.MaxDestroy))
+		}
+	}
+	if p.MaxChanges != nil {
+		if n := len(summary.Changes) - len(summary.withAction("no-op")); n > *p.MaxChanges {
+			result.Matched = true
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%d resources changed, exceeds max_changes %d", n, *p.MaxChanges))
+		}
+	}
+	for _, c := range summary.Changes {
+		if c.Action == "no-op" {
+			continue
+		}
+		if matchesAny(p.ForbidResourceTypes, c.Type) {
+			result.Matched = true
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%s (%s) matches a forbidden resource type", c.Address, c.Type))
+		}
+		if len(p.RequiredResourceTypesForApproval) > 0 && matchesAny(p.RequiredResourceTypesForApproval, c.Type) {
+			result.Matched = true
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%s (%s) requires approval", c.Address, c.Type))
+		}
+	}
+
+	return result
+}
+
+// EvaluatePolicies evaluates every policy against summary and returns the
+// additive set of matched labels alongside every matched PolicyResult and
+// the highest exit code among the matched policies (0 if none matched).
+func EvaluatePolicies(policies []Policy, summary PlanSummary) (labels []string, results []PolicyResult, exitCode int) {
+	for _, p := range policies {
+		result := p.Evaluate(summary)
+		if !result.Matched {
+			continue
+		}
+		results = append(results, result)
+		if p.Label != "" {
+			labels = append(labels, p.Label)
+		}
+		if p.ExitCode > exitCode {
+			exitCode = p.ExitCode
+		}
+	}
+	return labels, results, exitCode
+}
+
+// matchesAny reports whether resourceType matches any of patterns, where a
+// trailing "*" in a pattern matches any prefix (e.g. "aws_iam_*")
+func matchesAny(patterns []string, resourceType string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "*") {
+			if strings.HasPrefix(resourceType, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+			continue
+		}
+		if pattern == resourceType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package config
+
+import "testing"
+
+func intPtr(n int) *int { return &n }
+
+func TestMatchesAny(t *testing.T) {
+	testCases := []struct {
+		name         string
+		patterns     []string
+		resourceType string
+		want         bool
+	}{
+		{name: "exact match", patterns: []string{"aws_rds_instance"}, resourceType: "aws_rds_instance", want: true},
+		{name: "no match", patterns: []string{"aws_rds_instance"}, resourceType: "aws_s3_bucket", want: false},
+		{name: "wildcard suffix match", patterns: []string{"aws_iam_*"}, resourceType: "aws_iam_role", want: true},
+		{name: "wildcard suffix no match", patterns: []string{"aws_iam_*"}, resourceType: "aws_rds_instance", want: false},
+		{name: "empty patterns", patterns: nil, resourceType: "aws_rds_instance", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matchesAny(tc.patterns, tc.resourceType); got != tc.want {
+				t.Errorf("matchesAny(%v, %q) = %v, want %v", tc.patterns, tc.resourceType, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	summary := PlanSummary{
+		Changes: []ResourceChange{
+			{Address: "aws_instance.a", Type: "aws_instance", Action: "create"},
+			{Address: "aws_rds_instance.b", Type: "aws_rds_instance", Action: "delete"},
+			{Address: "aws_iam_role.c", Type: "aws_iam_role", Action: "update"},
+			{Address: "aws_instance.d", Type: "aws_instance", Action: "no-op"},
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		policy  Policy
+		matched bool
+	}{
+		{
+			name:    "max_destroy exceeded",
+			policy:  Policy{MaxDestroy: intPtr(0)},
+			matched: true,
+		},
+		{
+			name:    "max_destroy not exceeded",
+			policy:  Policy{MaxDestroy: intPtr(5)},
+			matched: false,
+		},
+		{
+			name:    "max_changes exceeded",
+			policy:  Policy{MaxChanges: intPtr(2)},
+			matched: true,
+		},
+		{
+			name:    "forbidden resource type present",
+			policy:  Policy{ForbidResourceTypes: []string{"aws_rds_instance"}},
+			matched: true,
+		},
+		{
+			name:    "forbidden resource type absent",
+			policy:  Policy{ForbidResourceTypes: []string{"aws_s3_bucket"}},
+			matched: false,
+		},
+		{
+			name:    "required resource type for approval present",
+			policy:  Policy{RequiredResourceTypesForApproval: []string{"aws_iam_*"}},
+			matched: true,
+		},
+		{
+			name:    "no rules set",
+			policy:  Policy{},
+			matched: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := tc.policy.Evaluate(summary)
+			if result.Matched != tc.matched {
+				t.Errorf("Evaluate() matched = %v, want %v (reasons: %v)", result.Matched, tc.matched, result.Reasons)
+			}
+		})
+	}
+}
+
+func TestEvaluatePolicies(t *testing.T) {
+	summary := PlanSummary{
+		Changes: []ResourceChange{
+			{Address: "aws_rds_instance.b", Type: "aws_rds_instance", Action: "delete"},
+			{Address: "aws_iam_role.c", Type: "aws_iam_role", Action: "update"},
+		},
+	}
+
+	policies := []Policy{
+		{Label: "forbidden-type", ForbidResourceTypes: []string{"aws_rds_instance"}, ExitCode: 1},
+		{Label: "needs-approval", RequiredResourceTypesForApproval: []string{"aws_iam_*"}, ExitCode: 2},
+		{Label: "never-matches", MaxDestroy: intPtr(10)},
+	}
+
+	labels, results, exitCode := EvaluatePolicies(policies, summary)
+
+	if len(labels) != 2 || labels[0] != "forbidden-type" || labels[1] != "needs-approval" {
+		t.Errorf("labels = %v, want [forbidden-type needs-approval]", labels)
+	}
+	if len(results) != 2 {
+		t.Errorf("len(results) = %d, want 2", len(results))
+	}
+	if exitCode != 2 {
+		t.Errorf("exitCode = %d, want 2 (the highest among matched policies)", exitCode)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// loadTemplateFiles resolves the template_file field on Default/Fmt/Plan/Apply
+// into Template, relative to the directory the config file lives in. An
+// inline Template takes precedence over template_file when both are set.
+func (cfg *Config) loadTemplateFiles() error {
+	dir := filepath.Dir(cfg.path)
+
+	merge := func(template, templateFile string) (string, error) {
+		if templateFile == "" || template != "" {
+			return template, nil
+		}
+		return cfg.readTemplateFile(dir, templateFile)
+	}
+
+	var err error
+	if cfg.Terraform.Default.Template, err = merge(cfg.Terraform.Default.Template, cfg.Terraform.Default.TemplateFile); err != nil {
+		return err
+	}
+	if cfg.Terraform.Fmt.Template, err = merge(cfg.Terraform.Fmt.Template, cfg.Terraform.Fmt.TemplateFile); err != nil {
+		return err
+	}
+	if cfg.Terraform.Plan.Template, err = merge(cfg.Terraform.Plan.Template, cfg.Terraform.Plan.TemplateFile); err != nil {
+		return err
+	}
+	if cfg.Terraform.Apply.Template, err = merge(cfg.Terraform.Apply.Template, cfg.Terraform.Apply.TemplateFile); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cfg *Config) readTemplateFile(dir, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("template_file %s: %w", path, err)
+	}
+	return string(raw), nil
+}
+
+// FuncMap returns the function map available to every tfnotify template:
+// sprig's general-purpose helpers (which already include "env") plus a
+// "file" function for inlining another file's contents at render time.
+func (cfg *Config) FuncMap() template.FuncMap {
+	dir := filepath.Dir(cfg.path)
+	funcs := sprig.TxtFuncMap()
+	funcs["file"] = func(path string) (string, error) {
+		return cfg.readTemplateFile(dir, path)
+	}
+	return funcs
+}
+
+// BuildTemplate parses content as a named template, with every partial
+// declared in Templates registered alongside it so content can reference
+// them via {{ template "name" . }}.
+func (cfg *Config) BuildTemplate(name, content string) (*template.Template, error) {
+	tpl := template.New(name).Funcs(cfg.FuncMap())
+	for partialName, body := range cfg.Templates {
+		if _, err := tpl.New(partialName).Parse(body); err != nil {
+			return nil, fmt.Errorf("parse template %q: %w", partialName, err)
+		}
+	}
+	return tpl.Parse(content)
+}
+
+// Render renders a named template (see BuildTemplate) against data and
+// returns the resulting string.
+func (cfg *Config) Render(name, content string, data interface{}) (string, error) {
+	tpl, err := cfg.BuildTemplate(name, content)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render template %q: %w", name, err)
+	}
+	return buf.String(), nil
+}
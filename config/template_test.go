@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTemplateFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "plan.tmpl"), []byte("plan from file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		plan    Plan
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "template_file is loaded when template is empty",
+			plan: Plan{TemplateFile: "plan.tmpl"},
+			want: "plan from file",
+		},
+		{
+			name: "inline template takes precedence over template_file",
+			plan: Plan{Template: "inline", TemplateFile: "plan.tmpl"},
+			want: "inline",
+		},
+		{
+			name: "neither set is a no-op",
+			plan: Plan{},
+			want: "",
+		},
+		{
+			name:    "missing template_file is a clear error",
+			plan:    Plan{TemplateFile: "missing.tmpl"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{path: filepath.Join(dir, "tfnotify.yaml")}
+			cfg.Terraform.Plan = tc.plan
+
+			err := cfg.loadTemplateFiles()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("loadTemplateFiles() = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadTemplateFiles() = %v, want nil", err)
+			}
+			if cfg.Terraform.Plan.Template != tc.want {
+				t.Errorf("Terraform.Plan.Template = %q, want %q", cfg.Terraform.Plan.Template, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderWithPartials(t *testing.T) {
+	cfg := &Config{
+		path: filepath.Join(t.TempDir(), "tfnotify.yaml"),
+		Templates: map[string]string{
+			"header": "== {{ .Title }} ==",
+		},
+	}
+
+	got, err := cfg.Render("plan", `{{ template "header" . }}\n{{ .Body }}`, struct {
+		Title string
+		Body  string
+	}{Title: "plan", Body: "no changes"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	want := `== plan ==\nno changes`
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderFileFunc(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "included.txt"), []byte("included content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{path: filepath.Join(dir, "tfnotify.yaml")}
+
+	got, err := cfg.Render("default", `{{ file "included.txt" }}`, nil)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got != "included content" {
+		t.Errorf("Render() = %q, want %q", got, "included content")
+	}
+}
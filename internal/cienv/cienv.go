@@ -0,0 +1,134 @@
+// Package cienv detects Bitbucket Pipelines, Azure DevOps Pipelines,
+// Buildkite, and AWS CodePipeline from their native environment variables.
+package cienv
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Platform is the subset of github.com/suzuki-shunsuke/go-ci-env's
+// cienv.Platform that tfnotify relies on
+type Platform interface {
+	CI() string
+	RepoOwner() string
+	RepoName() string
+	PR() int
+}
+
+// Get detects the current CI platform from its native environment
+// variables, or returns nil if none of the supported platforms is running
+func Get() Platform {
+	switch {
+	case os.Getenv("BITBUCKET_REPO_SLUG") != "":
+		return bitbucket{}
+	case os.Getenv("BUILDKITE") != "":
+		return buildkite{}
+	case os.Getenv("SYSTEM_PULLREQUEST_PULLREQUESTNUMBER") != "" || os.Getenv("TF_BUILD") != "":
+		return azureDevOps{}
+	case os.Getenv("CODEPIPELINE_EXECUTION_ID") != "" || strings.HasPrefix(os.Getenv("CODEBUILD_INITIATOR"), "codepipeline"):
+		return codePipeline{}
+	}
+	return nil
+}
+
+// GetByName returns the Platform for name, or nil if name isn't one of the
+// platforms this package supports
+func GetByName(name string) Platform {
+	switch strings.ToLower(name) {
+	case "bitbucket", "bitbucket-pipelines":
+		return bitbucket{}
+	case "buildkite":
+		return buildkite{}
+	case "azure-devops", "azuredevops":
+		return azureDevOps{}
+	case "codepipeline", "aws-codepipeline":
+		return codePipeline{}
+	}
+	return nil
+}
+
+type bitbucket struct{}
+
+func (bitbucket) CI() string        { return "bitbucket-pipelines" }
+func (bitbucket) RepoOwner() string { return os.Getenv("BITBUCKET_REPO_OWNER") }
+func (bitbucket) RepoName() string  { return os.Getenv("BITBUCKET_REPO_SLUG") }
+func (bitbucket) PR() int           { return atoi(os.Getenv("BITBUCKET_PR_ID")) }
+
+type buildkite struct{}
+
+func (buildkite) CI() string        { return "buildkite" }
+func (buildkite) RepoOwner() string { return os.Getenv("BUILDKITE_ORGANIZATION_SLUG") }
+func (buildkite) RepoName() string  { return os.Getenv("BUILDKITE_PIPELINE_SLUG") }
+func (buildkite) PR() int           { return atoi(os.Getenv("BUILDKITE_PULL_REQUEST")) }
+
+type azureDevOps struct{}
+
+func (azureDevOps) CI() string { return "azure-devops" }
+func (azureDevOps) RepoOwner() string {
+	return os.Getenv("SYSTEM_TEAMPROJECT")
+}
+func (azureDevOps) RepoName() string {
+	return os.Getenv("BUILD_REPOSITORY_NAME")
+}
+func (azureDevOps) PR() int {
+	return atoi(os.Getenv("SYSTEM_PULLREQUEST_PULLREQUESTNUMBER"))
+}
+
+// codePipeline supports a CodeBuild stage running inside an AWS CodePipeline,
+// where the PR number is parsed out of CODEBUILD_SOURCE_VERSION (format
+// "pr/123") the same way the existing "codebuild" CI type does for plain
+// CodeBuild PR builds
+type codePipeline struct{}
+
+func (codePipeline) CI() string { return "codepipeline" }
+func (codePipeline) RepoOwner() string {
+	return repoOwnerFromURL(os.Getenv("CODEBUILD_SOURCE_REPO_URL"))
+}
+func (codePipeline) RepoName() string {
+	return repoNameFromURL(os.Getenv("CODEBUILD_SOURCE_REPO_URL"))
+}
+func (codePipeline) PR() int {
+	version := os.Getenv("CODEBUILD_SOURCE_VERSION")
+	if !strings.HasPrefix(version, "pr/") {
+		return 0
+	}
+	return atoi(strings.TrimPrefix(version, "pr/"))
+}
+
+func repoOwnerFromURL(url string) string {
+	owner, _ := splitRepoURL(url)
+	return owner
+}
+
+func repoNameFromURL(url string) string {
+	_, name := splitRepoURL(url)
+	return name
+}
+
+// splitRepoURL extracts "owner", "name" out of a git remote URL, whether
+// it's an SSH form (git@github.com:owner/name.git) or HTTPS form
+// (https://github.com/owner/name.git)
+func splitRepoURL(url string) (owner, name string) {
+	url = strings.TrimSuffix(url, ".git")
+	url = strings.TrimSuffix(url, "/")
+	if i := strings.LastIndex(url, ":"); i != -1 && !strings.Contains(url[i:], "//") {
+		url = url[i+1:]
+	} else if i := strings.Index(url, "//"); i != -1 {
+		rest := url[i+2:]
+		if j := strings.Index(rest, "/"); j != -1 {
+			url = rest[j+1:]
+		}
+	}
+	parts := strings.Split(url, "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[len(parts)-2], parts[len(parts)-1]
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,58 @@
+package cienv
+
+import "testing"
+
+func TestSplitRepoURL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		url       string
+		wantOwner string
+		wantName  string
+	}{
+		{
+			name:      "https with .git suffix",
+			url:       "https://github.com/mercari/tfnotify.git",
+			wantOwner: "mercari",
+			wantName:  "tfnotify",
+		},
+		{
+			name:      "https without .git suffix",
+			url:       "https://github.com/mercari/tfnotify",
+			wantOwner: "mercari",
+			wantName:  "tfnotify",
+		},
+		{
+			name:      "ssh form",
+			url:       "git@github.com:mercari/tfnotify.git",
+			wantOwner: "mercari",
+			wantName:  "tfnotify",
+		},
+		{
+			name:      "trailing slash",
+			url:       "https://github.com/mercari/tfnotify/",
+			wantOwner: "mercari",
+			wantName:  "tfnotify",
+		},
+		{
+			name:      "empty",
+			url:       "",
+			wantOwner: "",
+			wantName:  "",
+		},
+		{
+			name:      "no owner/name separator",
+			url:       "tfnotify",
+			wantOwner: "",
+			wantName:  "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			owner, name := splitRepoURL(tc.url)
+			if owner != tc.wantOwner || name != tc.wantName {
+				t.Errorf("splitRepoURL(%q) = (%q, %q), want (%q, %q)", tc.url, owner, name, tc.wantOwner, tc.wantName)
+			}
+		})
+	}
+}
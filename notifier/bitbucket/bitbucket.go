@@ -0,0 +1,62 @@
+// Package bitbucket posts pull request comments via the Bitbucket Cloud REST API.
+package bitbucket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mercari/tfnotify/config"
+)
+
+const defaultBaseURL = "https://api.bitbucket.org/2.0"
+
+// Notifier is a notifier for Bitbucket Cloud pull request comments
+type Notifier struct {
+	Config config.BitbucketNotifier
+	Client *http.Client
+}
+
+// NewNotifier takes BitbucketNotifier and returns a new Notifier
+func NewNotifier(cfg config.BitbucketNotifier) *Notifier {
+	return &Notifier{
+		Config: cfg,
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Notify posts body as a comment on the given pull request
+func (n *Notifier) Notify(prNumber int, body string) error {
+	baseURL := n.Config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	url := fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d/comments",
+		baseURL, n.Config.Repository.Owner, n.Config.Repository.Name, prNumber)
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"content": map[string]string{"raw": body},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(n.Config.Username, n.Config.Token)
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("bitbucket: post pull request comment returned status %s", resp.Status)
+	}
+	return nil
+}
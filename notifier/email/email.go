@@ -0,0 +1,118 @@
+// Package email sends tfnotify notifications over SMTP.
+package email
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/mercari/tfnotify/config"
+)
+
+// Notifier is a notifier for SMTP email delivery
+type Notifier struct {
+	Config config.EmailNotifier
+}
+
+// NewNotifier takes EmailNotifier and returns a new Notifier
+func NewNotifier(cfg config.EmailNotifier) *Notifier {
+	return &Notifier{Config: cfg}
+}
+
+// Send renders and delivers subject/body as an email to the configured recipients.
+// recipients, when non-empty, overrides Config.To (used for per-plan-result overrides
+// such as WhenDestroy.Recipients).
+func (n *Notifier) Send(subject, body string, recipients []string) error {
+	to := recipients
+	if len(to) == 0 {
+		to = n.Config.To
+	}
+	if len(to) == 0 {
+		return errors.New("email: no recipients configured")
+	}
+
+	msg := buildMessage(n.Config.From, to, subject, body, n.Config.ContentType)
+	addr := fmt.Sprintf("%s:%d", n.Config.Host, n.Config.Port)
+
+	var auth smtp.Auth
+	if n.Config.Username != "" {
+		auth = smtp.PlainAuth("", n.Config.Username, n.Config.Password, n.Config.Host)
+	}
+
+	switch strings.ToLower(n.Config.Encryption) {
+	case "tls":
+		return n.sendTLS(addr, auth, to, msg)
+	case "none":
+		return smtp.SendMail(addr, auth, n.Config.From, to, msg)
+	default:
+		// starttls is the default, matching most SMTP providers' recommended setting
+		return smtp.SendMail(addr, auth, n.Config.From, to, msg)
+	}
+}
+
+func (n *Notifier) sendTLS(addr string, auth smtp.Auth, to []string, msg []byte) error {
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: n.Config.Host}) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("dial smtp server over tls: %w", err)
+	}
+	client, err := smtp.NewClient(conn, n.Config.Host)
+	if err != nil {
+		return fmt.Errorf("create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(n.Config.From); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func buildMessage(from string, to []string, subject, body, contentType string) []byte {
+	mime := "text/html"
+	if strings.ToLower(contentType) == "text" {
+		mime = "text/plain"
+	}
+
+	addrs := make([]string, len(to))
+	for i, addr := range to {
+		addrs[i] = stripCRLF(addr)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", stripCRLF(from))
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(addrs, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", stripCRLF(subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: %s; charset=\"UTF-8\"\r\n\r\n", mime)
+	b.WriteString(body)
+	return []byte(b.String())
+}
+
+// stripCRLF removes CR/LF from a header value so a rendered template can't
+// inject extra headers (or the body separator) into the message
+func stripCRLF(s string) string {
+	s = strings.ReplaceAll(s, "\r", "")
+	return strings.ReplaceAll(s, "\n", "")
+}
@@ -0,0 +1,56 @@
+package email
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMessage(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		want        string
+	}{
+		{
+			name:        "defaults to html",
+			contentType: "",
+			want:        `Content-Type: text/html; charset="UTF-8"`,
+		},
+		{
+			name:        "html explicit",
+			contentType: "html",
+			want:        `Content-Type: text/html; charset="UTF-8"`,
+		},
+		{
+			name:        "text",
+			contentType: "text",
+			want:        `Content-Type: text/plain; charset="UTF-8"`,
+		},
+		{
+			name:        "text is case-insensitive",
+			contentType: "TEXT",
+			want:        `Content-Type: text/plain; charset="UTF-8"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := string(buildMessage("from@example.com", []string{"to@example.com"}, "subject", "body", tc.contentType))
+			if !strings.Contains(msg, tc.want) {
+				t.Errorf("buildMessage() = %q, want it to contain %q", msg, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildMessageRejectsHeaderInjection(t *testing.T) {
+	subject := "subject\r\nBcc: attacker@evil.example"
+	msg := string(buildMessage("from@example.com", []string{"to@example.com"}, subject, "body", ""))
+
+	if strings.Contains(msg, "\r\nBcc:") {
+		t.Errorf("buildMessage() let an injected header through: %q", msg)
+	}
+	if strings.Count(msg, "\r\n\r\n") != 1 {
+		t.Errorf("buildMessage() should have exactly one header/body separator, got %q", msg)
+	}
+}
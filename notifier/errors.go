@@ -0,0 +1,25 @@
+// Package notifier holds types shared across the notification destinations
+// (github, gitlab, slack, typetalk, email, ...).
+package notifier
+
+import "strings"
+
+// Errors aggregates the failures collected while fanning a single plan/apply
+// result out to multiple notifiers. A failure in one notifier must not stop
+// the others from running, so callers collect into Errors instead of
+// returning on the first error.
+type Errors []error
+
+// Error implements the error interface
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// HasErrors reports whether any notifier failed
+func (e Errors) HasErrors() bool {
+	return len(e) > 0
+}
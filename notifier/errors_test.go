@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorsHasErrors(t *testing.T) {
+	if (Errors{}).HasErrors() {
+		t.Error("HasErrors() on an empty Errors = true, want false")
+	}
+	if !(Errors{errors.New("boom")}).HasErrors() {
+		t.Error("HasErrors() on a non-empty Errors = false, want true")
+	}
+}
+
+func TestErrorsError(t *testing.T) {
+	e := Errors{errors.New("github failed"), errors.New("slack failed")}
+	want := "github failed; slack failed"
+	if got := e.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestErrorsErrorSingle(t *testing.T) {
+	e := Errors{errors.New("github failed")}
+	if got := e.Error(); got != "github failed" {
+		t.Errorf("Error() = %q, want %q", got, "github failed")
+	}
+}
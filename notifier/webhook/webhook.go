@@ -0,0 +1,112 @@
+// Package webhook posts a templated payload to an arbitrary HTTP endpoint.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mercari/tfnotify/config"
+)
+
+// TemplateData is the data made available to config.WebhookNotifier.Body,
+// mirroring the variables already available to the terraform plan/apply templates.
+type TemplateData struct {
+	Body           string
+	Result         string
+	Link           string
+	CombinedOutput string
+}
+
+// Notifier is a notifier that posts a rendered payload to a webhook URL
+type Notifier struct {
+	Config config.WebhookNotifier
+	Client *http.Client
+}
+
+// NewNotifier takes WebhookNotifier and returns a new Notifier
+func NewNotifier(cfg config.WebhookNotifier) *Notifier {
+	return &Notifier{
+		Config: cfg,
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Notify renders Config.Body with data and delivers it to Config.URL,
+// retrying according to Config.Retry on transport errors or any response
+// status of 300 or above.
+func (n *Notifier) Notify(data TemplateData) error {
+	payload, err := n.render(data)
+	if err != nil {
+		return fmt.Errorf("render webhook body: %w", err)
+	}
+
+	attempts := n.Config.Retry.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+	backoff := time.Duration(n.Config.Retry.BackoffSecs) * time.Second
+
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		if i > 0 && backoff > 0 {
+			time.Sleep(backoff)
+		}
+		if lastErr = n.send(payload); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+func (n *Notifier) render(data TemplateData) ([]byte, error) {
+	tpl, err := template.New("webhook").Parse(n.Config.Body)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *Notifier) send(payload []byte) error {
+	method := n.Config.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequest(method, n.Config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Config.Headers {
+		req.Header.Set(k, v)
+	}
+	if n.Config.Secret != "" {
+		req.Header.Set("X-Tfnotify-Signature", sign(n.Config.Secret, payload))
+	}
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %s", n.Config.URL, resp.Status)
+	}
+	return nil
+}
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + strings.ToLower(hex.EncodeToString(mac.Sum(nil)))
+}
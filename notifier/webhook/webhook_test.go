@@ -0,0 +1,50 @@
+package webhook
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	testCases := []struct {
+		name    string
+		secret  string
+		payload string
+		want    string
+	}{
+		{
+			name:    "known vector",
+			secret:  "secret",
+			payload: "payload",
+			want:    "sha256=b82fcb791acec57859b989b430a826488ce2e479fdf92326bd0a2e8375a42ba4",
+		},
+		{
+			name:    "empty payload",
+			secret:  "secret",
+			payload: "",
+			want:    "sha256=f9e66e179b6747ae54108f82f8ade8b3c25d76fd30afde6c395822c530196169",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := sign(tc.secret, []byte(tc.payload))
+			if got != tc.want {
+				t.Errorf("sign(%q, %q) = %q, want %q", tc.secret, tc.payload, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSignIsDeterministic(t *testing.T) {
+	a := sign("secret", []byte("payload"))
+	b := sign("secret", []byte("payload"))
+	if a != b {
+		t.Errorf("sign() is not deterministic: %q != %q", a, b)
+	}
+}
+
+func TestSignDiffersPerSecret(t *testing.T) {
+	a := sign("secret-a", []byte("payload"))
+	b := sign("secret-b", []byte("payload"))
+	if a == b {
+		t.Errorf("sign() produced the same signature for different secrets: %q", a)
+	}
+}